@@ -0,0 +1,131 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains API Schema definitions for the hive v1alpha1 API group.
+package v1alpha1
+
+import (
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FinalizerDeprovision is the finalizer placed on a ClusterDeployment to ensure its cloud
+	// infrastructure is uninstalled before the object is removed from etcd.
+	FinalizerDeprovision string = "hive.openshift.io/deprovision"
+)
+
+// Platform identifies the cloud provider a ClusterDeployment is (or will be) installed on.
+type Platform string
+
+const (
+	// PlatformAWS identifies a cluster installed on Amazon Web Services.
+	PlatformAWS Platform = "AWS"
+	// PlatformGCP identifies a cluster installed on Google Cloud Platform.
+	PlatformGCP Platform = "GCP"
+	// PlatformAzure identifies a cluster installed on Microsoft Azure.
+	PlatformAzure Platform = "Azure"
+	// PlatformOpenStack identifies a cluster installed on OpenStack.
+	PlatformOpenStack Platform = "OpenStack"
+)
+
+// ClusterDeploymentSpec defines the desired state of ClusterDeployment.
+type ClusterDeploymentSpec struct {
+	// Platform is the cloud provider this cluster is installed on. It determines which
+	// MetadataParser the controller uses to interpret the installer's metadata.json.
+	Platform Platform `json:"platform,omitempty"`
+}
+
+// ClusterDeploymentStatus defines the observed state of ClusterDeployment.
+type ClusterDeploymentStatus struct {
+	// Installed is true once the install Job has completed successfully.
+	Installed bool `json:"installed,omitempty"`
+
+	// ClusterUUID is the cluster's unique identifier, as reported by the installer. Not every
+	// platform's metadata.json carries a distinct identifier of this kind; see MetadataParser.
+	ClusterUUID string `json:"clusterUUID,omitempty"`
+
+	// InfraID is the infrastructure name the installer generated for the cluster's cloud
+	// resources.
+	InfraID string `json:"infraID,omitempty"`
+
+	// Region is the cloud region the cluster was installed into, if the platform has one.
+	Region string `json:"region,omitempty"`
+
+	// Platform holds cloud-specific metadata read out of the installer's metadata.json for
+	// platforms without a dedicated MetadataParser, so operators can still inspect what the
+	// installer produced even though Hive doesn't understand the platform's identifiers well
+	// enough to populate ClusterUUID/InfraID/Region above.
+	Platform ClusterDeploymentPlatformStatus `json:"platform,omitempty"`
+
+	// Conditions describes the state of the ClusterDeployment.
+	Conditions []ClusterDeploymentCondition `json:"conditions,omitempty"`
+}
+
+// ClusterDeploymentPlatformStatus holds a cluster's whole metadata.json for platforms Hive has
+// no MetadataParser for.
+type ClusterDeploymentPlatformStatus struct {
+	// RawMetadata is the decoded metadata.json re-encoded as compact JSON (object keys sorted,
+	// whitespace dropped), recorded when no dedicated MetadataParser recognizes the
+	// ClusterDeployment's platform.
+	RawMetadata string `json:"rawMetadata,omitempty"`
+}
+
+// ClusterDeploymentConditionType is a valid value for ClusterDeploymentCondition.Type.
+type ClusterDeploymentConditionType string
+
+const (
+	// ClusterUninstalledCondition is True once the uninstall Job triggered by the
+	// hive.openshift.io/uninstall annotation has completed successfully.
+	ClusterUninstalledCondition ClusterDeploymentConditionType = "Uninstalled"
+
+	// PreTerminateHooksPendingCondition is True while one or more
+	// pre-terminate.hive.openshift.io/* annotations remain on the ClusterDeployment, blocking
+	// creation of the uninstall Job.
+	PreTerminateHooksPendingCondition ClusterDeploymentConditionType = "PreTerminateHooksPending"
+)
+
+// ClusterDeploymentCondition contains details for the current condition of a ClusterDeployment.
+type ClusterDeploymentCondition struct {
+	// Type is the type of the condition.
+	Type ClusterDeploymentConditionType `json:"type"`
+	// Status is the status of the condition.
+	Status kapi.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time this condition was checked.
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition's status changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about the last transition.
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterDeployment is the Schema for the clusterdeployments API.
+type ClusterDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterDeploymentSpec   `json:"spec,omitempty"`
+	Status ClusterDeploymentStatus `json:"status,omitempty"`
+}
+
+// ClusterDeploymentList contains a list of ClusterDeployment.
+type ClusterDeploymentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterDeployment `json:"items"`
+}
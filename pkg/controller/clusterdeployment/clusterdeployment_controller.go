@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -44,6 +46,7 @@ import (
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
 	"github.com/openshift/hive/pkg/install"
+	"github.com/openshift/hive/pkg/util/finalizers"
 )
 
 const (
@@ -59,11 +62,16 @@ const (
 
 	// deleteAfterAnnotation is the annotation that contains a duration after which the cluster should be cleaned up.
 	deleteAfterAnnotation = "hive.openshift.io/delete-after"
+
+	// uninstallAnnotation, when set to "true", tells the controller to tear down the cluster's
+	// cloud infrastructure without deleting the ClusterDeployment object itself.
+	uninstallAnnotation = "hive.openshift.io/uninstall"
 )
 
 // Add creates a new ClusterDeployment Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
+	RegisterFinalizer(hivev1.FinalizerDeprovision, (*ReconcileClusterDeployment).finalizeUninstall)
 	return AddToManager(mgr, NewReconciler(mgr))
 }
 
@@ -136,6 +144,22 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 	cdLog.Info("reconciling cluster deployment")
 	cd = cd.DeepCopy()
 
+	// Only ensure the finalizer on objects that are not already being deleted: an object that
+	// reaches here with a DeletionTimestamp and no finalizer (e.g. deleted before the controller
+	// ever reconciled it) should be left to ordinary garbage collection, not re-added to the
+	// deprovision flow.
+	if cd.DeletionTimestamp == nil {
+		added, result, err := finalizers.EnsureFinalizer(context.TODO(), r.Client, cd, hivev1.FinalizerDeprovision)
+		if err != nil {
+			cdLog.WithError(err).Error("error ensuring clusterdeployment finalizer")
+			return reconcile.Result{}, err
+		}
+		if added {
+			cdLog.Debug("added clusterdeployment finalizer")
+			return result, nil
+		}
+	}
+
 	_, err = r.setupClusterInstallServiceAccount(cd.Namespace, cdLog)
 	if err != nil {
 		cdLog.WithError(err).Error("error setting up service account and role")
@@ -143,12 +167,13 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 	}
 
 	if cd.DeletionTimestamp != nil {
-		if !HasFinalizer(cd, hivev1.FinalizerDeprovision) {
-			return reconcile.Result{}, nil
-		}
 		return r.syncDeletedClusterDeployment(cd, cdLog)
 	}
 
+	if cd.Annotations[uninstallAnnotation] == "true" {
+		return r.syncUninstallAnnotation(cd, cdLog)
+	}
+
 	// requeueAfter will be used to determine if cluster should be requeued after
 	// reconcile has completed
 	var requeueAfter time.Duration
@@ -175,11 +200,6 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 		}
 	}
 
-	if !HasFinalizer(cd, hivev1.FinalizerDeprovision) {
-		cdLog.Debugf("adding clusterdeployment finalizer")
-		return reconcile.Result{}, r.addClusterDeploymentFinalizer(cd)
-	}
-
 	job := install.GenerateInstallerJob(cd, serviceAccountName, installerImage, kapi.PullAlways,
 		hiveImage, kapi.PullIfNotPresent)
 
@@ -226,63 +246,188 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 	return reconcile.Result{}, nil
 }
 
+// updateClusterDeploymentStatus re-derives the ClusterDeployment's status from the observed
+// install Job (and, once installed, the metadata ConfigMap) and persists it via the /status
+// subresource. The read-modify-write is retried on conflict so that status updates never clobber
+// (or get clobbered by) concurrent spec, annotation, or finalizer changes to the same object.
 func (r *ReconcileClusterDeployment) updateClusterDeploymentStatus(cd *hivev1.ClusterDeployment, job *kbatch.Job, cdLog log.FieldLogger) error {
 	cdLog.Debug("updating cluster deployment status")
-	origCD := cd
-	cd = cd.DeepCopy()
-	if job != nil {
-		// Job exists, check it's status:
-		cd.Status.Installed = isSuccessful(job)
-	}
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &hivev1.ClusterDeployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, current); err != nil {
+			return err
+		}
+		origStatus := current.Status
 
-	if cd.Status.Installed {
-		if cd.Status.ClusterUUID == "" {
+		if job != nil {
+			// Job exists, check it's status:
+			current.Status.Installed = isSuccessful(job)
+		}
+
+		if current.Status.Installed && current.Status.ClusterUUID == "" && current.Status.InfraID == "" && current.Status.Platform.RawMetadata == "" {
 			metadataCfgMap := &kapi.ConfigMap{}
-			configMapName := fmt.Sprintf("%s-metadata", cd.Name)
-			err := r.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: cd.Namespace}, metadataCfgMap)
+			configMapName := fmt.Sprintf("%s-metadata", current.Name)
+			err := r.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: current.Namespace}, metadataCfgMap)
 			if err != nil {
 				// This would be pretty strange for a cluster that is installed:
 				cdLog.WithField("configmap", configMapName).WithError(err).Warn("error looking up metadata configmap")
 				return err
 			}
 
-			// Dynamically parse the JSON to get the UUID we need:
-			var objMap map[string]interface{}
-			if err := json.Unmarshal([]byte(metadataCfgMap.Data["metadata.json"]), &objMap); err != nil {
-				cdLog.WithError(err).Error("error reading json from metadata")
+			metadata, err := metadataParserFor(current).Parse([]byte(metadataCfgMap.Data["metadata.json"]), current)
+			if err != nil {
+				cdLog.WithError(err).Error("error parsing cluster metadata")
 				return err
 			}
-			aws, ok := objMap["aws"].(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("cluster metadata did not contain aws.identifier.tectonicClusterID")
-			}
-			identifier, ok := aws["identifier"].(map[string]interface{})
-			if !ok {
-				return fmt.Errorf("cluster metadata did not contain aws.identifier.tectonicClusterID")
-			}
-			cd.Status.ClusterUUID, ok = identifier["tectonicClusterID"].(string)
-			if !ok {
-				return fmt.Errorf("cluster metadata did not contain aws.identifier.tectonicClusterID")
+			current.Status.ClusterUUID = metadata.ClusterUUID
+			current.Status.InfraID = metadata.InfraID
+			current.Status.Region = metadata.Region
+			if current.Status.ClusterUUID == "" && current.Status.InfraID == "" {
+				// No dedicated MetadataParser recognized this platform, so genericMetadataParser
+				// only gave us the raw metadata.json. Record it as a sentinel so future
+				// reconciles don't keep re-fetching and re-parsing the configmap forever.
+				raw, err := json.Marshal(metadata.Raw)
+				if err != nil {
+					cdLog.WithError(err).Error("error marshaling raw cluster metadata")
+					return err
+				}
+				current.Status.Platform.RawMetadata = string(raw)
 			}
 		}
-	}
 
-	// Update cluster deployment status if changed:
-	if !reflect.DeepEqual(cd.Status, origCD.Status) {
+		// Update cluster deployment status if changed:
+		if reflect.DeepEqual(current.Status, origStatus) {
+			cdLog.Infof("cluster deployment status unchanged")
+			return nil
+		}
+
 		cdLog.Infof("status has changed, updating cluster deployment")
-		err := r.Update(context.TODO(), cd)
+		if err := r.Status().Update(context.TODO(), current); err != nil {
+			cdLog.Errorf("error updating cluster deployment status: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// syncDeletedClusterDeployment walks the registered finalizers still present on cd and invokes
+// each one's FinalizeFunc. A finalizer key is only removed from the object once its callback
+// reports success; a callback that is still working returns a non-empty Result, which is
+// propagated to the caller so the finalizer is retried on the next reconcile.
+func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
+	if blocked, result, err := r.guardPreTerminateHooks(cd, cdLog); blocked || err != nil {
+		return result, err
+	}
+
+	for _, entry := range clusterDeploymentFinalizers.entries {
+		if !HasFinalizer(cd, entry.key) {
+			continue
+		}
+		entryLog := cdLog.WithField("finalizer", entry.key)
+		result, err := entry.finalize(r, cd, entryLog)
 		if err != nil {
-			cdLog.Errorf("error updating cluster deployment: %v", err)
+			entryLog.WithError(err).Error("error running finalizer")
+			return reconcile.Result{}, err
+		}
+		if result.Requeue || result.RequeueAfter != 0 {
+			return result, nil
+		}
+		entryLog.Info("finalizer complete, removing")
+		if err := r.removeClusterDeploymentFinalizer(cd, entry.key); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// guardPreTerminateHooks blocks any path that is about to wipe cd's cloud infrastructure
+// (finalizeUninstall, reached via either the deprovision finalizer or the uninstall annotation)
+// while pre-terminate hook annotations are still present, recording which hooks are outstanding
+// in the PreTerminateHooksPendingCondition. blocked is true when the caller must return result,
+// err as-is instead of proceeding to finalizeUninstall.
+func (r *ReconcileClusterDeployment) guardPreTerminateHooks(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (blocked bool, result reconcile.Result, err error) {
+	if hooks := pendingPreTerminateHooks(cd); len(hooks) > 0 {
+		cdLog.WithField("hooks", hooks).Info("pre-terminate hooks pending, blocking uninstall")
+		if err := r.setPreTerminateHooksPending(cd, hooks); err != nil {
+			return true, reconcile.Result{}, err
+		}
+		return true, reconcile.Result{Requeue: true}, nil
+	}
+
+	if isConditionTrue(cd, hivev1.PreTerminateHooksPendingCondition) {
+		if err := r.clearPreTerminateHooksPending(cd); err != nil {
+			return false, reconcile.Result{}, err
+		}
+	}
+	return false, reconcile.Result{}, nil
+}
+
+// setPreTerminateHooksPending records the outstanding pre-terminate hook keys in a
+// PreTerminateHooksPending condition so operators and tooling can see what is still blocking
+// uninstall.
+func (r *ReconcileClusterDeployment) setPreTerminateHooksPending(cd *hivev1.ClusterDeployment, hooks []string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &hivev1.ClusterDeployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, current); err != nil {
 			return err
 		}
-	} else {
-		cdLog.Infof("cluster deployment status unchanged")
+		setClusterDeploymentCondition(current, hivev1.PreTerminateHooksPendingCondition, kapi.ConditionTrue,
+			"HooksPending", fmt.Sprintf("waiting for pre-terminate hooks to be removed: %s", strings.Join(hooks, ", ")))
+		return r.Status().Update(context.TODO(), current)
+	})
+}
+
+// clearPreTerminateHooksPending marks the PreTerminateHooksPending condition False once all
+// pre-terminate hook annotations have been removed.
+func (r *ReconcileClusterDeployment) clearPreTerminateHooksPending(cd *hivev1.ClusterDeployment) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &hivev1.ClusterDeployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, current); err != nil {
+			return err
+		}
+		setClusterDeploymentCondition(current, hivev1.PreTerminateHooksPendingCondition, kapi.ConditionFalse,
+			"HooksCleared", "all pre-terminate hooks have been removed")
+		return r.Status().Update(context.TODO(), current)
+	})
+}
+
+// syncUninstallAnnotation handles the hive.openshift.io/uninstall=true annotation, which lets an
+// operator tear down a cluster's cloud infrastructure while keeping the ClusterDeployment object
+// around for audit/reuse. It runs the same uninstall flow as the built-in finalizer, gated by the
+// same pre-terminate hooks, but on success it clears Status.Installed and records an Uninstalled
+// condition instead of removing a finalizer. Once the condition is set, re-applying the
+// annotation is a no-op.
+func (r *ReconcileClusterDeployment) syncUninstallAnnotation(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
+	if isConditionTrue(cd, hivev1.ClusterUninstalledCondition) {
+		cdLog.Debug("cluster already uninstalled, uninstall annotation is a no-op")
+		return reconcile.Result{}, nil
 	}
 
-	return nil
+	if blocked, result, err := r.guardPreTerminateHooks(cd, cdLog); blocked || err != nil {
+		return result, err
+	}
+
+	result, err := r.finalizeUninstall(cd, cdLog)
+	if err != nil || result.Requeue || result.RequeueAfter != 0 {
+		return result, err
+	}
+
+	cdLog.Info("uninstall job successful, marking cluster uninstalled")
+	return reconcile.Result{}, retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &hivev1.ClusterDeployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, current); err != nil {
+			return err
+		}
+		current.Status.Installed = false
+		setClusterDeploymentCondition(current, hivev1.ClusterUninstalledCondition, kapi.ConditionTrue,
+			"UninstallComplete", "cluster infrastructure has been uninstalled")
+		return r.Status().Update(context.TODO(), current)
+	})
 }
 
-func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
+// finalizeUninstall is the built-in FinalizeFunc registered against hivev1.FinalizerDeprovision.
+// It spawns (or polls) the uninstaller Job and requeues until the Job reports success.
+func (r *ReconcileClusterDeployment) finalizeUninstall(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
 	// Generate an uninstall job:
 	uninstallJob, err := install.GenerateUninstallerJob(cd, installerImage, kapi.PullAlways)
 	if err != nil {
@@ -305,32 +450,34 @@ func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.Clu
 			cdLog.Errorf("error creating uninstall job: %v", err)
 			return reconcile.Result{}, err
 		}
-		return reconcile.Result{}, nil
+		return reconcile.Result{Requeue: true}, nil
 	} else if err != nil {
 		cdLog.Errorf("error getting uninstall job: %v", err)
 		return reconcile.Result{}, err
 	}
 
-	// Uninstall job exists, check it's status and if successful, remove the finalizer:
+	// Uninstall job exists, check it's status and if successful, report completion:
 	if isSuccessful(existingJob) {
-		cdLog.Infof("uninstall job successful, removing finalizer")
-		return reconcile.Result{}, r.removeClusterDeploymentFinalizer(cd)
+		cdLog.Infof("uninstall job successful")
+		return reconcile.Result{}, nil
 	}
 
 	cdLog.Infof("uninstall job not yet successful")
-	return reconcile.Result{}, nil
-}
-
-func (r *ReconcileClusterDeployment) addClusterDeploymentFinalizer(cd *hivev1.ClusterDeployment) error {
-	cd = cd.DeepCopy()
-	AddFinalizer(cd, hivev1.FinalizerDeprovision)
-	return r.Update(context.TODO(), cd)
+	return reconcile.Result{Requeue: true}, nil
 }
 
-func (r *ReconcileClusterDeployment) removeClusterDeploymentFinalizer(cd *hivev1.ClusterDeployment) error {
-	cd = cd.DeepCopy()
-	DeleteFinalizer(cd, hivev1.FinalizerDeprovision)
-	return r.Update(context.TODO(), cd)
+// removeClusterDeploymentFinalizer re-Gets the ClusterDeployment and removes the given
+// finalizer on each retry attempt so that finalizer churn does not collide with concurrent
+// status updates.
+func (r *ReconcileClusterDeployment) removeClusterDeploymentFinalizer(cd *hivev1.ClusterDeployment, finalizer string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &hivev1.ClusterDeployment{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, current); err != nil {
+			return err
+		}
+		DeleteFinalizer(current, finalizer)
+		return r.Update(context.TODO(), current)
+	})
 }
 
 // setupClusterInstallServiceAccount ensures a service account exists which can upload
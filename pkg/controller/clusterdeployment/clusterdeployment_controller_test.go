@@ -0,0 +1,231 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kbatch "k8s.io/api/batch/v1"
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+const (
+	testName      = "foo"
+	testNamespace = "default"
+)
+
+func testClusterDeployment() *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testName,
+			Namespace:  testNamespace,
+			Finalizers: []string{},
+		},
+	}
+}
+
+// conflictOnceClient wraps a client.Client and returns a Conflict error from the first call to
+// Update (and the first call to the status subresource's Update) before delegating to the
+// wrapped client on subsequent calls.
+type conflictOnceClient struct {
+	client.Client
+	updateConflicted bool
+	statusConflicted bool
+}
+
+func (c *conflictOnceClient) Update(ctx context.Context, obj runtime.Object) error {
+	if !c.updateConflicted {
+		c.updateConflicted = true
+		return errors.NewConflict(schema.GroupResource{Resource: "clusterdeployments"}, testName, nil)
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func (c *conflictOnceClient) Status() client.StatusWriter {
+	return &conflictOnceStatusWriter{client: c}
+}
+
+type conflictOnceStatusWriter struct {
+	client *conflictOnceClient
+}
+
+func (w *conflictOnceStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	if !w.client.statusConflicted {
+		w.client.statusConflicted = true
+		return errors.NewConflict(schema.GroupResource{Resource: "clusterdeployments"}, testName, nil)
+	}
+	return w.client.Client.Status().Update(ctx, obj)
+}
+
+func newTestReconciler(initObjs ...runtime.Object) (*ReconcileClusterDeployment, *conflictOnceClient) {
+	fakeClient := fake.NewFakeClient(initObjs...)
+	cc := &conflictOnceClient{Client: fakeClient}
+	return &ReconcileClusterDeployment{Client: cc}, cc
+}
+
+func TestUpdateClusterDeploymentStatusRetriesOnConflict(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Spec.Platform = hivev1.PlatformAWS
+	job := &kbatch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-install", Namespace: testNamespace},
+		Status: kbatch.JobStatus{
+			Conditions: []kbatch.JobCondition{
+				{Type: kbatch.JobComplete, Status: kapi.ConditionTrue},
+			},
+		},
+	}
+	cfgMap := &kapi.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-metadata", Namespace: testNamespace},
+		Data:       map[string]string{"metadata.json": `{"aws":{"identifier":{"tectonicClusterID":"test-uuid"}}}`},
+	}
+
+	r, cc := newTestReconciler(cd, cfgMap)
+	logger, _ := logtest.NewNullLogger()
+	err := r.updateClusterDeploymentStatus(cd, job, logger)
+	assert.NoError(t, err)
+	assert.True(t, cc.statusConflicted, "expected a conflict to have been injected")
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.True(t, updated.Status.Installed)
+	assert.Equal(t, "test-uuid", updated.Status.ClusterUUID)
+}
+
+func TestUpdateClusterDeploymentStatusRecordsRawMetadataOnceForUnknownPlatform(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Spec.Platform = hivev1.Platform("unknown")
+	job := &kbatch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-install", Namespace: testNamespace},
+		Status: kbatch.JobStatus{
+			Conditions: []kbatch.JobCondition{
+				{Type: kbatch.JobComplete, Status: kapi.ConditionTrue},
+			},
+		},
+	}
+	cfgMap := &kapi.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo-metadata", Namespace: testNamespace},
+		Data:       map[string]string{"metadata.json": `{"someCloud":{"id":"abc"}}`},
+	}
+
+	r, _ := newTestReconciler(cd, cfgMap)
+	logger, _ := logtest.NewNullLogger()
+	err := r.updateClusterDeploymentStatus(cd, job, logger)
+	assert.NoError(t, err)
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, updated.Status.Platform.RawMetadata, "expected raw metadata to be recorded for an unrecognized platform")
+
+	// A second reconcile must not re-fetch and re-parse the configmap now that the sentinel is
+	// set: deleting the configmap and re-running would surface a Get error if it tried.
+	err = r.Delete(context.TODO(), cfgMap)
+	assert.NoError(t, err)
+	err = r.updateClusterDeploymentStatus(&updated, job, logger)
+	assert.NoError(t, err)
+}
+
+func TestReconcileFirstPassOnlyPatchesFinalizer(t *testing.T) {
+	cd := testClusterDeployment()
+	r, _ := newTestReconciler(cd)
+
+	result, err := r.Reconcile(reconcile.Request{
+		NamespacedName: client.ObjectKey{Name: testName, Namespace: testNamespace},
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.True(t, HasFinalizer(&updated, hivev1.FinalizerDeprovision))
+
+	var sa kapi.ServiceAccount
+	err = r.Get(context.TODO(), client.ObjectKey{Name: serviceAccountName, Namespace: testNamespace}, &sa)
+	assert.True(t, errors.IsNotFound(err), "expected no service account to have been created on the finalizer-only pass")
+
+	var job kbatch.Job
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName + "-install", Namespace: testNamespace}, &job)
+	assert.True(t, errors.IsNotFound(err), "expected no install job to have been created on the finalizer-only pass")
+}
+
+func TestReconcileDoesNotAddFinalizerToAlreadyDeletingObject(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.DeletionTimestamp = &metav1.Time{}
+	r, _ := newTestReconciler(cd)
+
+	result, err := r.Reconcile(reconcile.Request{
+		NamespacedName: client.ObjectKey{Name: testName, Namespace: testNamespace},
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue, "a deleting object with no finalizer should not be requeued to add one")
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.False(t, HasFinalizer(&updated, hivev1.FinalizerDeprovision), "finalizer should not be added to an object already being deleted")
+
+	var job kbatch.Job
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName + "-uninstall", Namespace: testNamespace}, &job)
+	assert.True(t, errors.IsNotFound(err), "expected no uninstall job for an object with no finalizer to clean up")
+}
+
+func TestRemoveClusterDeploymentFinalizerRetriesOnConflict(t *testing.T) {
+	cd := testClusterDeployment()
+	AddFinalizer(cd, hivev1.FinalizerDeprovision)
+	r, cc := newTestReconciler(cd)
+
+	err := r.removeClusterDeploymentFinalizer(cd, hivev1.FinalizerDeprovision)
+	assert.NoError(t, err)
+	assert.True(t, cc.updateConflicted, "expected a conflict to have been injected")
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.False(t, HasFinalizer(&updated, hivev1.FinalizerDeprovision))
+}
+
+func TestSyncUninstallAnnotationIsNoopOnceUninstalled(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Annotations = map[string]string{uninstallAnnotation: "true"}
+	setClusterDeploymentCondition(cd, hivev1.ClusterUninstalledCondition, kapi.ConditionTrue, "UninstallComplete", "already done")
+	r, _ := newTestReconciler(cd)
+	logger, _ := logtest.NewNullLogger()
+
+	result, err := r.syncUninstallAnnotation(cd, logger)
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue)
+
+	var job kbatch.Job
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName + "-uninstall", Namespace: testNamespace}, &job)
+	assert.True(t, errors.IsNotFound(err), "expected no uninstall job to have been created")
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+// findClusterDeploymentCondition returns a pointer to cd's condition of the given type, or nil
+// if it does not have one yet.
+func findClusterDeploymentCondition(cd *hivev1.ClusterDeployment, condType hivev1.ClusterDeploymentConditionType) *hivev1.ClusterDeploymentCondition {
+	for i, c := range cd.Status.Conditions {
+		if c.Type == condType {
+			return &cd.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// isConditionTrue reports whether cd has the given condition set to True.
+func isConditionTrue(cd *hivev1.ClusterDeployment, condType hivev1.ClusterDeploymentConditionType) bool {
+	cond := findClusterDeploymentCondition(cd, condType)
+	return cond != nil && cond.Status == kapi.ConditionTrue
+}
+
+// setClusterDeploymentCondition creates or updates the condition of the given type on cd,
+// bumping LastTransitionTime only when the status actually changes.
+func setClusterDeploymentCondition(cd *hivev1.ClusterDeployment, condType hivev1.ClusterDeploymentConditionType, status kapi.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	cond := findClusterDeploymentCondition(cd, condType)
+	if cond == nil {
+		cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+			Type:               condType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+		})
+		return
+	}
+	if cond.Status != status {
+		cond.LastTransitionTime = now
+	}
+	cond.Status = status
+	cond.Reason = reason
+	cond.Message = message
+	cond.LastProbeTime = now
+}
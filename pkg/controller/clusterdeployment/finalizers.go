@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+// FinalizeFunc performs the cleanup work associated with a single finalizer key. While its key
+// remains present on a deleted ClusterDeployment, it is invoked on each reconcile in which every
+// finalizer registered before it has already completed; it is not invoked while an earlier one
+// is still in progress. It may return a non-empty Result to indicate that cleanup is still in
+// progress and should be requeued rather than treated as complete.
+type FinalizeFunc func(r *ReconcileClusterDeployment, cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error)
+
+// finalizerEntry pairs a stable finalizer key with the callback that performs its cleanup.
+type finalizerEntry struct {
+	key      string
+	finalize FinalizeFunc
+}
+
+// finalizerRegistry holds the ordered set of finalizers that run when a ClusterDeployment is
+// deleted. Entries are invoked in registration order; each one is removed from the object only
+// once its FinalizeFunc reports success.
+type finalizerRegistry struct {
+	entries []finalizerEntry
+}
+
+// register adds a finalizer to the registry.
+func (fr *finalizerRegistry) register(key string, finalize FinalizeFunc) {
+	fr.entries = append(fr.entries, finalizerEntry{key: key, finalize: finalize})
+}
+
+// clusterDeploymentFinalizers is the process-wide registry used by the ClusterDeployment
+// controller. Add registers the built-in uninstall finalizer against it; downstream consumers
+// wiring up this controller can call RegisterFinalizer to add their own before calling Add.
+var clusterDeploymentFinalizers = &finalizerRegistry{}
+
+// RegisterFinalizer adds a cleanup callback, keyed by finalizer name, to the ClusterDeployment
+// controller's finalizer registry. Finalizers run in registration order, so one registered
+// before Add runs ahead of the built-in uninstall finalizer that Add registers, and one
+// registered after Add runs after it. The registry is not guarded by a lock, so registration
+// happens while wiring up the controller, before reconciles start.
+func RegisterFinalizer(key string, finalize FinalizeFunc) {
+	clusterDeploymentFinalizers.register(key, finalize)
+}
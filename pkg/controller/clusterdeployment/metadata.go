@@ -0,0 +1,172 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"encoding/json"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+// ClusterMetadata holds the cloud-specific identifiers extracted from the installer's
+// metadata.json for a single cluster.
+type ClusterMetadata struct {
+	// ClusterUUID is the cluster's unique identifier, used elsewhere in Hive to correlate a
+	// ClusterDeployment with the cluster it provisioned. Left unset by parsers for platforms
+	// whose metadata.json carries no identifier distinct from InfraID, rather than duplicating
+	// InfraID into it.
+	ClusterUUID string
+	// InfraID is the infrastructure name the installer generated for cloud resources.
+	InfraID string
+	// Region is the cloud region the cluster was installed into, if the platform has one.
+	Region string
+	// Raw is the fully decoded metadata.json, kept around for platforms Hive does not yet
+	// understand specifically.
+	Raw map[string]interface{}
+}
+
+// MetadataParser extracts ClusterMetadata from the raw contents of an installer metadata.json.
+// Implementations are registered in metadataParsers, keyed by the cloud platform they
+// understand.
+type MetadataParser interface {
+	Parse(raw []byte, cd *hivev1.ClusterDeployment) (ClusterMetadata, error)
+}
+
+// metadataParsers maps a cloud platform to the MetadataParser that understands its
+// metadata.json shape. metadataParserFor falls back to genericMetadataParser for any platform
+// with no entry here, rather than failing reconciliation outright.
+var metadataParsers = map[hivev1.Platform]MetadataParser{
+	hivev1.PlatformAWS:       awsMetadataParser{},
+	hivev1.PlatformGCP:       gcpMetadataParser{},
+	hivev1.PlatformAzure:     azureMetadataParser{},
+	hivev1.PlatformOpenStack: openStackMetadataParser{},
+}
+
+// metadataParserFor returns the MetadataParser registered for cd's platform, falling back to
+// genericMetadataParser if no dedicated parser is registered.
+func metadataParserFor(cd *hivev1.ClusterDeployment) MetadataParser {
+	if parser, ok := metadataParsers[cd.Spec.Platform]; ok {
+		return parser
+	}
+	return genericMetadataParser{}
+}
+
+// unmarshalMetadata decodes the raw metadata.json contents shared by every parser.
+func unmarshalMetadata(raw []byte) (map[string]interface{}, error) {
+	var objMap map[string]interface{}
+	if err := json.Unmarshal(raw, &objMap); err != nil {
+		return nil, fmt.Errorf("error reading json from metadata: %v", err)
+	}
+	return objMap, nil
+}
+
+// stringField walks a chain of nested object keys and returns the string found at the end, or
+// false if any key along the way is missing or not the expected type.
+func stringField(objMap map[string]interface{}, keys ...string) (string, bool) {
+	cur := objMap
+	for i, key := range keys {
+		if i == len(keys)-1 {
+			s, ok := cur[key].(string)
+			return s, ok
+		}
+		next, ok := cur[key].(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		cur = next
+	}
+	return "", false
+}
+
+// awsMetadataParser parses metadata.json produced by installs on AWS.
+type awsMetadataParser struct{}
+
+func (awsMetadataParser) Parse(raw []byte, cd *hivev1.ClusterDeployment) (ClusterMetadata, error) {
+	objMap, err := unmarshalMetadata(raw)
+	if err != nil {
+		return ClusterMetadata{}, err
+	}
+	uuid, ok := stringField(objMap, "aws", "identifier", "tectonicClusterID")
+	if !ok {
+		return ClusterMetadata{}, fmt.Errorf("cluster metadata did not contain aws.identifier.tectonicClusterID")
+	}
+	infraID, _ := stringField(objMap, "aws", "identifier", "infraID")
+	region, _ := stringField(objMap, "aws", "region")
+	return ClusterMetadata{ClusterUUID: uuid, InfraID: infraID, Region: region, Raw: objMap}, nil
+}
+
+// gcpMetadataParser parses metadata.json produced by installs on Google Cloud Platform.
+type gcpMetadataParser struct{}
+
+func (gcpMetadataParser) Parse(raw []byte, cd *hivev1.ClusterDeployment) (ClusterMetadata, error) {
+	objMap, err := unmarshalMetadata(raw)
+	if err != nil {
+		return ClusterMetadata{}, err
+	}
+	infraID, ok := stringField(objMap, "gcp", "identifier", "infraID")
+	if !ok {
+		return ClusterMetadata{}, fmt.Errorf("cluster metadata did not contain gcp.identifier.infraID")
+	}
+	region, _ := stringField(objMap, "gcp", "region")
+	return ClusterMetadata{InfraID: infraID, Region: region, Raw: objMap}, nil
+}
+
+// azureMetadataParser parses metadata.json produced by installs on Azure.
+type azureMetadataParser struct{}
+
+func (azureMetadataParser) Parse(raw []byte, cd *hivev1.ClusterDeployment) (ClusterMetadata, error) {
+	objMap, err := unmarshalMetadata(raw)
+	if err != nil {
+		return ClusterMetadata{}, err
+	}
+	infraID, ok := stringField(objMap, "azure", "identifier", "infraID")
+	if !ok {
+		return ClusterMetadata{}, fmt.Errorf("cluster metadata did not contain azure.identifier.infraID")
+	}
+	region, _ := stringField(objMap, "azure", "region")
+	return ClusterMetadata{InfraID: infraID, Region: region, Raw: objMap}, nil
+}
+
+// openStackMetadataParser parses metadata.json produced by installs on OpenStack.
+type openStackMetadataParser struct{}
+
+func (openStackMetadataParser) Parse(raw []byte, cd *hivev1.ClusterDeployment) (ClusterMetadata, error) {
+	objMap, err := unmarshalMetadata(raw)
+	if err != nil {
+		return ClusterMetadata{}, err
+	}
+	infraID, ok := stringField(objMap, "openstack", "identifier", "infraID")
+	if !ok {
+		return ClusterMetadata{}, fmt.Errorf("cluster metadata did not contain openstack.identifier.infraID")
+	}
+	region, _ := stringField(objMap, "openstack", "region")
+	return ClusterMetadata{InfraID: infraID, Region: region, Raw: objMap}, nil
+}
+
+// genericMetadataParser is used for any platform without a dedicated parser. It records the raw
+// metadata.json contents without failing reconciliation, since Hive does not yet understand the
+// platform-specific identifier layout.
+type genericMetadataParser struct{}
+
+func (genericMetadataParser) Parse(raw []byte, cd *hivev1.ClusterDeployment) (ClusterMetadata, error) {
+	objMap, err := unmarshalMetadata(raw)
+	if err != nil {
+		return ClusterMetadata{}, err
+	}
+	return ClusterMetadata{Raw: objMap}, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	raw, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("error reading testdata file %s: %v", name, err)
+	}
+	return raw
+}
+
+func TestMetadataParsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform hivev1.Platform
+		fixture  string
+		expected ClusterMetadata
+	}{
+		{
+			name:     "aws",
+			platform: hivev1.PlatformAWS,
+			fixture:  "metadata-aws.json",
+			expected: ClusterMetadata{ClusterUUID: "aws-cluster-uuid", InfraID: "test-cluster-abcde", Region: "us-east-1"},
+		},
+		{
+			name:     "gcp",
+			platform: hivev1.PlatformGCP,
+			fixture:  "metadata-gcp.json",
+			expected: ClusterMetadata{InfraID: "test-cluster-fghij", Region: "us-east1"},
+		},
+		{
+			name:     "azure",
+			platform: hivev1.PlatformAzure,
+			fixture:  "metadata-azure.json",
+			expected: ClusterMetadata{InfraID: "test-cluster-klmno", Region: "eastus"},
+		},
+		{
+			name:     "openstack",
+			platform: hivev1.PlatformOpenStack,
+			fixture:  "metadata-openstack.json",
+			expected: ClusterMetadata{InfraID: "test-cluster-pqrst", Region: "regionOne"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Spec.Platform = test.platform
+			raw := readTestdata(t, test.fixture)
+
+			metadata, err := metadataParserFor(cd).Parse(raw, cd)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected.ClusterUUID, metadata.ClusterUUID)
+			assert.Equal(t, test.expected.InfraID, metadata.InfraID)
+			assert.Equal(t, test.expected.Region, metadata.Region)
+		})
+	}
+}
+
+func TestGenericMetadataParserDoesNotFailOnUnknownPlatform(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Spec.Platform = hivev1.Platform("unknown")
+
+	metadata, err := metadataParserFor(cd).Parse(readTestdata(t, "metadata-aws.json"), cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "", metadata.ClusterUUID)
+	assert.NotNil(t, metadata.Raw)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"sort"
+	"strings"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+// preTerminateHookAnnotationPrefix marks an annotation as a named pre-terminate hook. As long as
+// any annotation with this prefix is present on a ClusterDeployment, syncDeletedClusterDeployment
+// blocks creation of the uninstall Job so that external actors (a workload evacuator, a backup
+// controller, an addon running a final in-cluster job) can interpose ordered teardown steps
+// between "user deleted the ClusterDeployment" and "installer wipes the cloud account".
+const preTerminateHookAnnotationPrefix = "pre-terminate.hive.openshift.io/"
+
+// pendingPreTerminateHooks returns the sorted list of pre-terminate hook names (the annotation
+// key with the preTerminateHookAnnotationPrefix stripped) still present on cd.
+func pendingPreTerminateHooks(cd *hivev1.ClusterDeployment) []string {
+	var hooks []string
+	for key := range cd.Annotations {
+		if strings.HasPrefix(key, preTerminateHookAnnotationPrefix) {
+			hooks = append(hooks, strings.TrimPrefix(key, preTerminateHookAnnotationPrefix))
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
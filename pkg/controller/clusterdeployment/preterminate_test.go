@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kbatch "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+// TestMain registers the built-in uninstall finalizer against the package-level
+// clusterDeploymentFinalizers registry before any test runs. In production this registration
+// happens inside Add, but Add also wires up a real manager/controller, which unit tests have no
+// need for and no way to construct; registering here is the minimal equivalent so that tests
+// exercising syncDeletedClusterDeployment see the same finalizer the controller would.
+func TestMain(m *testing.M) {
+	RegisterFinalizer(hivev1.FinalizerDeprovision, (*ReconcileClusterDeployment).finalizeUninstall)
+	os.Exit(m.Run())
+}
+
+func TestPendingPreTerminateHooks(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Annotations = map[string]string{
+		"pre-terminate.hive.openshift.io/evacuate-workloads": "",
+		"pre-terminate.hive.openshift.io/backup":             "",
+		"unrelated.example.com/other":                        "",
+	}
+	assert.Equal(t, []string{"backup", "evacuate-workloads"}, pendingPreTerminateHooks(cd))
+}
+
+func TestSyncDeletedClusterDeploymentBlocksOnPreTerminateHooks(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.DeletionTimestamp = &metav1.Time{}
+	cd.Annotations = map[string]string{"pre-terminate.hive.openshift.io/backup": ""}
+	AddFinalizer(cd, hivev1.FinalizerDeprovision)
+	r, _ := newTestReconciler(cd)
+	logger, _ := logtest.NewNullLogger()
+
+	result, err := r.syncDeletedClusterDeployment(cd, logger)
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	var job kbatch.Job
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName + "-uninstall", Namespace: testNamespace}, &job)
+	assert.True(t, errors.IsNotFound(err), "expected no uninstall job while hooks are pending")
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.True(t, isConditionTrue(&updated, hivev1.PreTerminateHooksPendingCondition))
+}
+
+func TestSyncUninstallAnnotationBlocksOnPreTerminateHooks(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Annotations = map[string]string{
+		uninstallAnnotation: "true",
+		"pre-terminate.hive.openshift.io/evacuate-workloads": "",
+	}
+	r, _ := newTestReconciler(cd)
+	logger, _ := logtest.NewNullLogger()
+
+	result, err := r.syncUninstallAnnotation(cd, logger)
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	var job kbatch.Job
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName + "-uninstall", Namespace: testNamespace}, &job)
+	assert.True(t, errors.IsNotFound(err), "expected no uninstall job while hooks are pending")
+
+	var updated hivev1.ClusterDeployment
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, &updated)
+	assert.NoError(t, err)
+	assert.True(t, isConditionTrue(&updated, hivev1.PreTerminateHooksPendingCondition))
+}
+
+func TestSyncDeletedClusterDeploymentProceedsOnceHooksCleared(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.DeletionTimestamp = &metav1.Time{}
+	AddFinalizer(cd, hivev1.FinalizerDeprovision)
+	r, _ := newTestReconciler(cd)
+	logger, _ := logtest.NewNullLogger()
+
+	result, err := r.syncDeletedClusterDeployment(cd, logger)
+	assert.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	var job kbatch.Job
+	err = r.Get(context.TODO(), client.ObjectKey{Name: testName + "-uninstall", Namespace: testNamespace}, &job)
+	assert.NoError(t, err, "expected the uninstall job to have been created")
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package finalizers provides a small helper for adding a finalizer to an object as an early,
+// self-contained step of Reconcile, before any side-effecting work that the finalizer is meant
+// to protect.
+package finalizers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Object is the minimal interface EnsureFinalizer needs from the object it patches: enough to
+// read/write finalizers and to be deep-copied and passed to the client as a patch base.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// EnsureFinalizer adds the named finalizer to obj if it is not already present, via a merge
+// patch that touches only metadata.finalizers. When it adds the finalizer, it returns
+// added=true and a Result requesting an immediate requeue: callers should return that Result
+// straight back to the controller-runtime and do no further work this reconcile, so that every
+// later phase of Reconcile can assume the finalizer is already present on the object.
+func EnsureFinalizer(ctx context.Context, c client.Client, obj Object, name string) (bool, reconcile.Result, error) {
+	for _, f := range obj.GetFinalizers() {
+		if f == name {
+			return false, reconcile.Result{}, nil
+		}
+	}
+
+	original := obj.DeepCopyObject()
+	obj.SetFinalizers(append(append([]string{}, obj.GetFinalizers()...), name))
+
+	if err := c.Patch(ctx, obj, client.MergeFrom(original)); err != nil {
+		return false, reconcile.Result{}, err
+	}
+
+	return true, reconcile.Result{Requeue: true}, nil
+}